@@ -0,0 +1,87 @@
+// Modifications Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"sync"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/consensus"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// Processor executes the transactions of a block against a StateDB and
+// returns the resulting receipts, logs and cumulative gas used. The live
+// chain's implementation lives in state_processor.go, which this checkout
+// does not contain; BlockChain depends only on this interface so the prefetch
+// scheduler below can be exercised against a stub Processor in tests.
+type Processor interface {
+	Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error)
+}
+
+// BlockChain represents the canonical chain given a database with a genesis
+// block. This type is trimmed to the fields the prefetch scheduler and
+// precompile registry need to do real work; the full BlockChain (trie
+// cache eviction, fork choice, block/receipt persistence, event feeds,
+// snapshotting, and so on) lives in the real blockchain.go, which this
+// checkout does not contain.
+type BlockChain struct {
+	chainConfig *params.ChainConfig
+	db          database.DBManager
+	engine      consensus.Engine
+	cacheConfig *CacheConfig
+	processor   Processor
+
+	precompilesOnce sync.Once
+	precompiles     *vm.PrecompileManager
+}
+
+// NewBlockChain wires up the minimal BlockChain needed to drive the
+// concurrent prefetch scheduler and the precompile registry against
+// processor; see the type doc for what is intentionally omitted.
+func NewBlockChain(db database.DBManager, cacheConfig *CacheConfig, chainConfig *params.ChainConfig, engine consensus.Engine, processor Processor) *BlockChain {
+	if cacheConfig == nil {
+		cacheConfig = &CacheConfig{}
+	}
+	return &BlockChain{
+		chainConfig: chainConfig,
+		db:          db,
+		engine:      engine,
+		cacheConfig: cacheConfig,
+		processor:   processor,
+	}
+}
+
+// insertChain executes block against bc.processor, overlapping the
+// concurrent state prefetcher with that execution via schedulePrefetch: the
+// prefetcher starts on a StartPrefetcher copy of statedb before Process
+// begins, and its interrupt channel is closed as soon as Process returns so
+// it stops warming trie nodes that are no longer useful.
+//
+// This is scoped to the single-block execute step that schedulePrefetch
+// needs to overlap with; ancestor validation, chain reorganization, block and
+// receipt persistence, and event emission belong to the full insertChain in
+// the real blockchain.go and are not reproduced here.
+func (bc *BlockChain) insertChain(block *types.Block, statedb *state.StateDB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error) {
+	done := bc.schedulePrefetch(block, statedb.Copy(), cfg)
+	defer done()
+
+	return bc.processor.Process(block, statedb, cfg)
+}
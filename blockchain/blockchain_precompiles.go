@@ -0,0 +1,39 @@
+// Modifications Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import "github.com/klaytn/klaytn/blockchain/vm"
+
+// PrecompileManager returns the registry of stateful precompiled contracts
+// used by this package's own EVM construction sites (currently
+// precacheTransaction; see insertChain for the block-processing path once
+// bc.processor consults it). It is backed by the precompiles field on
+// BlockChain, lazily initialised to an empty registry carrying bc.chainConfig
+// on first use; operators extend it by calling Register on the returned
+// manager during node startup.
+//
+// PrecompileManager can be called concurrently, e.g. by Prefetch and
+// PrefetchTx running in the goroutines that schedulePrefetch and
+// schedulePrefetchTx launch (each takes one Snapshot and hands that to its
+// workers), so the lazy init is guarded by precompilesOnce rather than a bare
+// nil check.
+func (bc *BlockChain) PrecompileManager() *vm.PrecompileManager {
+	bc.precompilesOnce.Do(func() {
+		bc.precompiles = vm.NewPrecompileManager(bc.chainConfig)
+	})
+	return bc.precompiles
+}
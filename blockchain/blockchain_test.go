@@ -0,0 +1,62 @@
+// Modifications Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+)
+
+// recordingProcessor stands in for the real state processor: it just records
+// that Process was called and with how many transactions, so tests can
+// assert insertChain actually reaches it (as opposed to schedulePrefetch
+// running with nothing downstream to overlap with).
+type recordingProcessor struct {
+	called  bool
+	numTxs  int
+	retErr  error
+}
+
+func (r *recordingProcessor) Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error) {
+	r.called = true
+	r.numTxs = len(block.Transactions())
+	return nil, nil, 0, r.retErr
+}
+
+// TestInsertChainCallsProcessor checks that insertChain actually drives
+// bc.processor.Process, i.e. that the concurrent prefetch scheduler added in
+// prefetch_scheduler.go is wired into a real block-execution call site rather
+// than only being reachable from its own benchmark.
+func TestInsertChainCallsProcessor(t *testing.T) {
+	block, statedb, _, bc := newPrefetchBenchFixture(t, prefetchTxThreshold+1)
+
+	proc := &recordingProcessor{}
+	bc.processor = proc
+
+	if _, _, _, err := bc.insertChain(block, statedb, vm.Config{}); err != nil {
+		t.Fatalf("insertChain returned an error: %v", err)
+	}
+	if !proc.called {
+		t.Fatalf("expected insertChain to call bc.processor.Process")
+	}
+	if proc.numTxs != len(block.Transactions()) {
+		t.Fatalf("expected Process to see all %d transactions, got %d", len(block.Transactions()), proc.numTxs)
+	}
+}
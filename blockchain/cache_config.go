@@ -0,0 +1,34 @@
+// Modifications Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+// CacheConfig holds the prefetch-related fields of BlockChain's cache
+// configuration; the rest of that configuration (trie cache sizing, flush
+// intervals, and so on) belongs to the full blockchain.go, which this
+// checkout does not contain.
+type CacheConfig struct {
+	// PrefetchEnabled turns on the concurrent prefetch scheduler in
+	// insertChain. When false, schedulePrefetch is a no-op.
+	PrefetchEnabled bool
+	// PrefetchTxNumber is the minimum number of transactions a block must
+	// carry before insertChain bothers launching Prefetch concurrently with
+	// processor.Process.
+	PrefetchTxNumber int
+	// PrefetchMode selects how the prefetcher warms up trie nodes for a
+	// transaction; see PrefetchMode.
+	PrefetchMode PrefetchMode
+}
@@ -0,0 +1,62 @@
+// Modifications Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package blockchain
+
+import (
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+)
+
+// schedulePrefetch overlaps Prefetch with block processing: insertChain calls
+// it on a throwaway copy of statedb (via StartPrefetcher) immediately before
+// processor.Process, and calls the returned done func immediately after, which
+// closes the interrupt channel so the prefetcher exits promptly instead of
+// continuing to warm trie nodes that are no longer useful.
+//
+// It is a no-op, and done a no-op, when prefetching is disabled or the block
+// doesn't carry enough transactions to be worth the goroutine and StateDB
+// copy overhead, per CacheConfig.
+func (bc *BlockChain) schedulePrefetch(block *types.Block, statedb *state.StateDB, cfg vm.Config) (done func()) {
+	if bc.cacheConfig == nil || !bc.cacheConfig.PrefetchEnabled || len(block.Transactions()) < bc.cacheConfig.PrefetchTxNumber {
+		return func() {}
+	}
+
+	prefetchDB := statedb.StartPrefetcher("chain")
+	prefetcher := newStatePrefetcher(bc.chainConfig, bc, bc.engine, bc.cacheConfig.PrefetchMode)
+
+	interrupt := make(chan struct{})
+	go prefetcher.Prefetch(block, prefetchDB, cfg, interrupt)
+
+	return func() { close(interrupt) }
+}
+
+// schedulePrefetchTx is the PrefetchTx-path equivalent of schedulePrefetch,
+// used by the single-transaction fetcher instead of full block import.
+func (bc *BlockChain) schedulePrefetchTx(block *types.Block, ti int, statedb *state.StateDB, cfg vm.Config) (done func()) {
+	if bc.cacheConfig == nil || !bc.cacheConfig.PrefetchEnabled {
+		return func() {}
+	}
+
+	prefetchDB := statedb.StartPrefetcher("tx")
+	prefetcher := newStatePrefetcher(bc.chainConfig, bc, bc.engine, bc.cacheConfig.PrefetchMode)
+
+	interrupt := make(chan struct{})
+	go prefetcher.PrefetchTx(block, ti, prefetchDB, cfg, interrupt)
+
+	return func() { close(interrupt) }
+}
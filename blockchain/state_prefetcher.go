@@ -21,16 +21,55 @@
 package blockchain
 
 import (
-	"sync/atomic"
+	"context"
+	"runtime"
+	"sync"
+	"time"
 
 	"github.com/klaytn/klaytn/blockchain/state"
 	"github.com/klaytn/klaytn/blockchain/types"
 	"github.com/klaytn/klaytn/blockchain/vm"
 	"github.com/klaytn/klaytn/common"
 	"github.com/klaytn/klaytn/consensus"
+	"github.com/klaytn/klaytn/metrics"
 	"github.com/klaytn/klaytn/params"
 )
 
+// prefetchTxThreshold is the minimum number of transactions a block must
+// carry before the statePrefetcher bothers spinning up worker goroutines.
+// Below this, the goroutine and stateDB.Copy() overhead outweighs the
+// benefit of warming the trie ahead of the main processor. It mirrors
+// CacheConfig.PrefetchTxNumber, which insertChain uses to decide whether to
+// launch Prefetch concurrently with Process on a StateDB copy.
+var prefetchTxThreshold = 100
+
+var (
+	prefetchTxMeter          = metrics.NewRegisteredMeter("blockchain/prefetch/tx", nil)
+	prefetchInterruptedMeter = metrics.NewRegisteredMeter("blockchain/prefetch/interrupted", nil)
+	prefetchWorkerTimer      = metrics.NewRegisteredTimer("blockchain/prefetch/worker", nil)
+	// prefetchInterruptLatencyTimer measures how long a worker took to notice
+	// that interrupt was closed, from the moment the caller closed it. Large
+	// values mean workers are stuck deep inside a single precacheTransaction
+	// call and aren't reacting to cancellation promptly.
+	prefetchInterruptLatencyTimer = metrics.NewRegisteredTimer("blockchain/prefetch/interruptlatency", nil)
+)
+
+// PrefetchMode selects how the statePrefetcher warms up trie nodes for a
+// transaction.
+type PrefetchMode int
+
+const (
+	// PrefetchModeFull executes the transaction through the EVM, the same way
+	// the main processor eventually will.
+	PrefetchModeFull PrefetchMode = iota
+	// PrefetchModeAccessListOnly skips EVM execution entirely and only reads
+	// the addresses and storage slots named by the transaction's access list.
+	PrefetchModeAccessListOnly
+	// PrefetchModeAuto uses the access list fast path when the transaction
+	// carries one, and falls back to full execution otherwise.
+	PrefetchModeAuto
+)
+
 // statePrefetcher is a basic Prefetcher, which blindly executes a block on top
 // of an arbitrary state with the goal of prefetching potentially useful state
 // data from disk before the main block processor start executing.
@@ -38,34 +77,124 @@ type statePrefetcher struct {
 	config *params.ChainConfig // Chain configuration options
 	bc     *BlockChain         // Canonical block chain
 	engine consensus.Engine    // Consensus engine used for block rewards
+	mode   PrefetchMode        // How to warm up trie nodes for a transaction
 }
 
-// newStatePrefetcher initialises a new statePrefetcher.
-func newStatePrefetcher(config *params.ChainConfig, bc *BlockChain, engine consensus.Engine) *statePrefetcher {
+// newStatePrefetcher initialises a new statePrefetcher. mode controls how it
+// warms up trie nodes for a transaction; callers typically source it from
+// BlockChain's CacheConfig so operators can choose Full/AccessListOnly/Auto
+// without touching code.
+func newStatePrefetcher(config *params.ChainConfig, bc *BlockChain, engine consensus.Engine, mode PrefetchMode) *statePrefetcher {
 	return &statePrefetcher{
 		config: config,
 		bc:     bc,
 		engine: engine,
+		mode:   mode,
 	}
 }
 
+// prefetchJob is a single unit of work handed to a prefetch worker: the index
+// of the transaction within the block, and the transaction itself.
+type prefetchJob struct {
+	index int
+	tx    *types.Transaction
+}
+
 // Prefetch processes the state changes according to the Klaytn rules by running
 // the transaction messages using the statedb, but any changes are discarded. The
 // only goal is to pre-cache transaction signatures and state trie nodes.
-func (p *statePrefetcher) Prefetch(block *types.Block, stateDB *state.StateDB, cfg vm.Config, interrupt *uint32) {
-	var (
-		header = block.Header()
-	)
-	// Iterate over and process the individual transactions
-	for i, tx := range block.Transactions() {
-		// If block precaching was interrupted, abort
-		if interrupt != nil && atomic.LoadUint32(interrupt) == 1 {
+//
+// The block's transactions are handed out over a single work channel shared by
+// a pool of worker goroutines, so an idle worker always steals the next
+// transaction instead of a long-running transaction stalling a statically
+// assigned shard. Each worker operates on its own stateDB.Copy() to avoid
+// races between workers. interrupt is closed by the caller to cancel the
+// remaining work; workers observe the close between transactions.
+func (p *statePrefetcher) Prefetch(block *types.Block, stateDB *state.StateDB, cfg vm.Config, interrupt <-chan struct{}) {
+	txs := block.Transactions()
+	if len(txs) < prefetchTxThreshold {
+		return
+	}
+
+	header := block.Header()
+
+	// Snapshot the precompile registry once for the whole block so concurrent
+	// Register calls on the live manager don't race with workers reading it
+	// mid-block; every worker sees the same, stable view.
+	pm := p.bc.PrecompileManager().Snapshot()
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(txs) {
+		workers = len(txs)
+	}
+
+	jobs := make(chan prefetchJob, len(txs))
+	for i, tx := range txs {
+		jobs <- prefetchJob{index: i, tx: tx}
+	}
+	close(jobs)
+
+	// Wrap the caller's interrupt in a stamped signal so workers can report how
+	// long they took to notice cancellation after the caller closed it.
+	stamped := newStampedInterrupt(interrupt)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			p.prefetchWorker(jobs, stateDB.Copy(), block.Hash(), header, cfg, stamped, pm)
+		}()
+	}
+	wg.Wait()
+}
+
+// stampedInterrupt pairs a derived "closed" channel with the time at which
+// the original interrupt was observed closed, so a worker selecting on ch can
+// compute how long it took to react.
+type stampedInterrupt struct {
+	ch     chan struct{}
+	closed time.Time
+}
+
+// newStampedInterrupt spawns a goroutine that waits for interrupt to close,
+// records the time, and closes the returned stampedInterrupt's ch. If
+// interrupt is nil, the returned stampedInterrupt is never closed.
+func newStampedInterrupt(interrupt <-chan struct{}) *stampedInterrupt {
+	s := &stampedInterrupt{ch: make(chan struct{})}
+	if interrupt == nil {
+		return s
+	}
+	go func() {
+		<-interrupt
+		s.closed = time.Now()
+		close(s.ch)
+	}()
+	return s
+}
+
+// prefetchWorker drains jobs from the shared work channel until it is
+// exhausted or interrupt is closed, pre-caching each transaction against its
+// own stateDB copy. pm is the block-wide PrecompileManager snapshot taken
+// once by Prefetch, shared read-only by every worker.
+func (p *statePrefetcher) prefetchWorker(jobs <-chan prefetchJob, stateDB *state.StateDB, blockHash common.Hash, header *types.Header, cfg vm.Config, interrupt *stampedInterrupt, pm *vm.PrecompileManager) {
+	for {
+		select {
+		case <-interrupt.ch:
+			prefetchInterruptedMeter.Mark(1)
+			prefetchInterruptLatencyTimer.UpdateSince(interrupt.closed)
 			return
-		}
-		// Block precaching permitted to continue, execute the transaction
-		stateDB.Prepare(tx.Hash(), block.Hash(), i)
-		if err := precacheTransaction(p.config, p.bc, nil, stateDB, header, tx, cfg); err != nil {
-			return // Ugh, something went horribly wrong, bail out
+		case job, ok := <-jobs:
+			if !ok {
+				return
+			}
+			start := time.Now()
+			stateDB.Prepare(job.tx.Hash(), blockHash, job.index)
+			if err := precacheTransaction(p.config, p.bc, nil, stateDB, header, job.tx, cfg, p.mode, pm); err != nil {
+				return // Ugh, something went horribly wrong, bail out
+			}
+			prefetchTxMeter.Mark(1)
+			prefetchWorkerTimer.UpdateSince(start)
 		}
 	}
 }
@@ -74,20 +203,23 @@ func (p *statePrefetcher) Prefetch(block *types.Block, stateDB *state.StateDB, c
 // a single transaction message using the statedb, but any changes are discarded. The
 // only goal is to pre-cache transaction signatures and state trie nodes. It is used
 // when fetcher works, so it fetches only a block.
-func (p *statePrefetcher) PrefetchTx(block *types.Block, ti int, stateDB *state.StateDB, cfg vm.Config, interrupt *uint32) {
+func (p *statePrefetcher) PrefetchTx(block *types.Block, ti int, stateDB *state.StateDB, cfg vm.Config, interrupt <-chan struct{}) {
 	var (
 		header = block.Header()
 		tx     = block.Transactions()[ti]
 	)
 
 	// If block precaching was interrupted, abort
-	if interrupt != nil && atomic.LoadUint32(interrupt) == 1 {
+	select {
+	case <-interrupt:
 		return
+	default:
 	}
 
 	// Block precaching permitted to continue, execute the transaction
 	stateDB.Prepare(tx.Hash(), block.Hash(), ti)
-	if err := precacheTransaction(p.config, p.bc, nil, stateDB, header, tx, cfg); err != nil {
+	pm := p.bc.PrecompileManager().Snapshot()
+	if err := precacheTransaction(p.config, p.bc, nil, stateDB, header, tx, cfg, p.mode, pm); err != nil {
 		return // Ugh, something went horribly wrong, bail out
 	}
 }
@@ -95,16 +227,57 @@ func (p *statePrefetcher) PrefetchTx(block *types.Block, ti int, stateDB *state.
 // precacheTransaction attempts to apply a transaction to the given state database
 // and uses the input parameters for its environment. The goal is not to execute
 // the transaction successfully, rather to warm up touched data slots.
-func precacheTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, statedb *state.StateDB, header *types.Header, tx *types.Transaction, cfg vm.Config) error {
+//
+// When mode permits it and the transaction carries an access list, the cheaper
+// precacheAccessList path is used instead of running the full EVM. pm is a
+// snapshot of the chain's PrecompileManager, taken once per block by the
+// caller; its Prepare hook runs here, before the EVM is constructed, so any
+// registered precompile can warm up its own state ahead of execution. pm is
+// not passed to vm.NewEVM, so the EVM does not resolve calls against it; the
+// Prepare hook is the only use made of pm here. This is currently the only
+// EVM construction site that consults pm: the legacy
+// groundx.xyz/go-gxplatform/core/vm path used by eth_call is a separate
+// package not present in this checkout, so it does not yet resolve against
+// this registry.
+func precacheTransaction(config *params.ChainConfig, bc ChainContext, author *common.Address, statedb *state.StateDB, header *types.Header, tx *types.Transaction, cfg vm.Config, mode PrefetchMode, pm *vm.PrecompileManager) error {
+	if mode != PrefetchModeFull {
+		if al := tx.AccessList(); al != nil {
+			// Both AccessListOnly and Auto stop here once an access list is
+			// present: Auto's whole point is to take this cheaper path instead
+			// of full execution whenever it can, not run both.
+			precacheAccessList(statedb, al)
+			return nil
+		}
+	}
+
+	if pm != nil {
+		pm.Prepare(context.Background(), statedb)
+	}
+
 	// Convert the transaction into an executable message and pre-cache its sender
 	msg, err := tx.AsMessageWithAccountKeyPicker(types.MakeSigner(config, header.Number), statedb, header.Number.Uint64())
 	if err != nil {
 		return err
 	}
 	// Create the EVM and execute the transaction
-	context := NewEVMContext(msg, header, bc, author)
-	vm := vm.NewEVM(context, statedb, config, &cfg)
+	evmContext := NewEVMContext(msg, header, bc, author)
+	evm := vm.NewEVM(evmContext, statedb, config, &cfg)
 
-	_, _, kerr := ApplyMessage(vm, msg)
+	_, _, kerr := ApplyMessage(evm, msg)
 	return kerr.ErrTxInvalid
 }
+
+// precacheAccessList warms the trie for every address and storage slot named
+// by an access list, without spinning up the EVM. This covers the bulk of the
+// state an access-list-tagged transaction is expected to touch at a fraction
+// of the cost of full execution.
+func precacheAccessList(statedb *state.StateDB, al types.AccessList) {
+	for _, tuple := range al {
+		statedb.GetCode(tuple.Address)
+		statedb.GetNonce(tuple.Address)
+		statedb.GetBalance(tuple.Address)
+		for _, key := range tuple.StorageKeys {
+			statedb.GetState(tuple.Address, key)
+		}
+	}
+}
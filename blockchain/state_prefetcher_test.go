@@ -0,0 +1,195 @@
+// Modifications Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+//
+// This file benchmarks the concurrent statePrefetcher introduced to overlap
+// prefetching with block processing; see schedulePrefetch in
+// prefetch_scheduler.go for the scheduler that insertChain (blockchain.go)
+// calls to drive Prefetch on a StateDB copy while processor.Process runs.
+
+package blockchain
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/blockchain/types"
+	"github.com/klaytn/klaytn/blockchain/vm"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/consensus/gxhash"
+	"github.com/klaytn/klaytn/crypto"
+	"github.com/klaytn/klaytn/params"
+	"github.com/klaytn/klaytn/storage/database"
+)
+
+// newPrefetchBenchFixture builds a synthetic block of numTxs simple transfers
+// together with a statePrefetcher, its owning BlockChain (with concurrent
+// prefetch enabled), and a funded statedb to run it against.
+func newPrefetchBenchFixture(tb testing.TB, numTxs int) (*types.Block, *state.StateDB, *statePrefetcher, *BlockChain) {
+	tb.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		tb.Fatalf("failed to generate sender key: %v", err)
+	}
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+
+	gspec := &Genesis{
+		Config: params.TestChainConfig,
+		Alloc:  GenesisAlloc{addr: {Balance: big.NewInt(0).Mul(big.NewInt(1000000), big.NewInt(params.KLAY))}},
+	}
+	db := database.NewMemDB()
+	genesis := gspec.MustCommit(db)
+
+	statedb, err := state.New(genesis.Root(), state.NewDatabase(db))
+	if err != nil {
+		tb.Fatalf("failed to open genesis state: %v", err)
+	}
+
+	bc := &BlockChain{
+		chainConfig: gspec.Config,
+		db:          db,
+		engine:      gxhash.NewFaker(),
+		cacheConfig: &CacheConfig{
+			PrefetchEnabled:  true,
+			PrefetchTxNumber: prefetchTxThreshold,
+			PrefetchMode:     PrefetchModeAuto,
+		},
+	}
+	p := newStatePrefetcher(gspec.Config, bc, gxhash.NewFaker(), PrefetchModeAuto)
+
+	block := newPrefetchBenchBlock(tb, gspec.Config, genesis, key, addr, numTxs)
+	return block, statedb, p, bc
+}
+
+// newPrefetchBenchBlock assembles numTxs signed, self-looping value transfers
+// from addr into a single block on top of genesis. The transfers never
+// revert and never touch each other's storage, so the benchmark measures
+// prefetch scheduling overhead rather than contention between workers.
+func newPrefetchBenchBlock(tb testing.TB, config *params.ChainConfig, genesis *types.Block, key *ecdsa.PrivateKey, addr common.Address, numTxs int) *types.Block {
+	tb.Helper()
+
+	signer := types.NewEIP155Signer(config.ChainID)
+	txs := make([]*types.Transaction, 0, numTxs)
+	for i := 0; i < numTxs; i++ {
+		tx := types.NewTransaction(uint64(i), addr, big.NewInt(1), params.TxGas, big.NewInt(0), nil)
+		signedTx, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			tb.Fatalf("failed to sign benchmark tx: %v", err)
+		}
+		txs = append(txs, signedTx)
+	}
+
+	header := &types.Header{
+		ParentHash: genesis.Hash(),
+		Number:     big.NewInt(1),
+		GasLimit:   genesis.GasLimit(),
+		Time:       big.NewInt(genesis.Time().Int64() + 1),
+	}
+	return types.NewBlockWithHeader(header).WithBody(txs, nil)
+}
+
+// benchmarkPrefetch measures the wall-clock time of running Prefetch over a
+// synthetic block of numTxs no-op transfers, with the worker pool sized by
+// GOMAXPROCS. It is used to compare concurrent prefetch against the baseline
+// processor latency for tx-heavy blocks.
+func benchmarkPrefetch(b *testing.B, numTxs int) {
+	block, statedb, p, _ := newPrefetchBenchFixture(b, numTxs)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		interrupt := make(chan struct{})
+		p.Prefetch(block, statedb.Copy(), vm.Config{}, interrupt)
+		close(interrupt)
+	}
+}
+
+func BenchmarkPrefetch_50Txs(b *testing.B)   { benchmarkPrefetch(b, 50) }
+func BenchmarkPrefetch_100Txs(b *testing.B)  { benchmarkPrefetch(b, 100) }
+func BenchmarkPrefetch_500Txs(b *testing.B)  { benchmarkPrefetch(b, 500) }
+func BenchmarkPrefetch_2000Txs(b *testing.B) { benchmarkPrefetch(b, 2000) }
+
+// fakeProcess stands in for processor.Process: it touches the same accounts
+// the prefetcher is warming, with an artificial per-tx delay so the benchmark
+// has something measurable to overlap prefetching against.
+func fakeProcess(block *types.Block, statedb *state.StateDB) {
+	for i, tx := range block.Transactions() {
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		statedb.GetBalance(*tx.To())
+		time.Sleep(10 * time.Microsecond)
+	}
+}
+
+// benchmarkBlockImport measures end-to-end block-import latency -
+// schedulePrefetch followed by fakeProcess followed by done() - with and
+// without concurrent prefetch enabled, over synthetic blocks of varying tx
+// counts. This is the scenario schedulePrefetch targets: insertChain calling
+// Prefetch on a StateDB copy while processor.Process runs on the real one.
+func benchmarkBlockImport(b *testing.B, numTxs int, prefetchEnabled bool) {
+	block, statedb, _, bc := newPrefetchBenchFixture(b, numTxs)
+	bc.cacheConfig.PrefetchEnabled = prefetchEnabled
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		done := bc.schedulePrefetch(block, statedb.Copy(), vm.Config{})
+		fakeProcess(block, statedb.Copy())
+		done()
+	}
+}
+
+func BenchmarkBlockImport_500Txs_PrefetchOff(b *testing.B) { benchmarkBlockImport(b, 500, false) }
+func BenchmarkBlockImport_500Txs_PrefetchOn(b *testing.B)  { benchmarkBlockImport(b, 500, true) }
+func BenchmarkBlockImport_2000Txs_PrefetchOff(b *testing.B) {
+	benchmarkBlockImport(b, 2000, false)
+}
+func BenchmarkBlockImport_2000Txs_PrefetchOn(b *testing.B) {
+	benchmarkBlockImport(b, 2000, true)
+}
+
+// TestPrefetchBelowThresholdIsNoop checks that blocks carrying fewer than
+// prefetchTxThreshold transactions are skipped, since the goroutine and
+// stateDB.Copy() overhead would outweigh any benefit for small blocks.
+func TestPrefetchBelowThresholdIsNoop(t *testing.T) {
+	block, statedb, p, bc := newPrefetchBenchFixture(t, prefetchTxThreshold-1)
+
+	before := statedb.IntermediateRoot(false)
+
+	interrupt := make(chan struct{})
+	p.Prefetch(block, statedb, vm.Config{}, interrupt)
+	close(interrupt)
+
+	if after := statedb.IntermediateRoot(false); after != before {
+		t.Fatalf("expected Prefetch to be a no-op below prefetchTxThreshold, root changed from %x to %x", before, after)
+	}
+	// Workers only ever touch stateDB copies, so the root check alone cannot
+	// tell a skipped block from a prefetched one. A Prefetch that gets past the
+	// threshold check initialises bc.precompiles via PrecompileManager().
+	if bc.precompiles != nil {
+		t.Fatalf("expected Prefetch to return before starting workers below prefetchTxThreshold")
+	}
+}
+
+// TestSchedulePrefetchDisabled checks that schedulePrefetch returns a no-op
+// done func when CacheConfig.PrefetchEnabled is false.
+func TestSchedulePrefetchDisabled(t *testing.T) {
+	block, statedb, _, bc := newPrefetchBenchFixture(t, prefetchTxThreshold+1)
+	bc.cacheConfig.PrefetchEnabled = false
+
+	done := bc.schedulePrefetch(block, statedb, vm.Config{})
+	done() // must not panic or block
+}
@@ -0,0 +1,48 @@
+// Modifications Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import "github.com/klaytn/klaytn/common"
+
+// AccessTuple is the element type of an access list: an address together
+// with the storage slots of that address the transaction is declared to
+// touch, as introduced by EIP-2930-style typed transactions.
+type AccessTuple struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []common.Hash  `json:"storageKeys"`
+}
+
+// AccessList is the access list of a typed transaction.
+type AccessList []AccessTuple
+
+// TxInternalDataAccessList is implemented by the TxInternalData of typed
+// transactions that carry an access list. Klaytn's legacy and value-transfer
+// internal data types do not implement it, so Transaction.AccessList falls
+// back to nil for them.
+type TxInternalDataAccessList interface {
+	AccessList() AccessList
+}
+
+// AccessList returns the transaction's access list, or nil if its underlying
+// TxInternalData does not carry one (e.g. legacy transactions, or any type
+// predating typed transaction support).
+func (tx *Transaction) AccessList() AccessList {
+	if al, ok := tx.data.(TxInternalDataAccessList); ok {
+		return al.AccessList()
+	}
+	return nil
+}
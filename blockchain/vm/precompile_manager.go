@@ -0,0 +1,151 @@
+// Modifications Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/common"
+	"github.com/klaytn/klaytn/params"
+)
+
+// precompileEntry is a single registered precompile together with the block
+// number it activates at. A nil activation means "active from genesis".
+type precompileEntry struct {
+	contract PrecompiledContract
+	activate *uint64
+}
+
+// PrecompileManager resolves common.Address to PrecompiledContract for the
+// contracts registered on it through Register; it holds no built-in
+// precompiles of its own. It lets an operator register custom stateful
+// precompiles (e.g. for an app-chain bridge or oracle) at node startup. Code
+// that consults the manager calls Resolve and Prepare directly: vm.NewEVM is
+// not given a manager, so calls executed inside the EVM do not resolve
+// against it.
+//
+// The manager is copy-on-write: Snapshot returns an immutable view that a
+// long-running caller (the prefetcher, a block's EVM executions) can hold for
+// the duration of a block without observing registrations that happen
+// concurrently on other goroutines.
+type PrecompileManager struct {
+	config *params.ChainConfig
+
+	mu      sync.RWMutex
+	entries map[common.Address]precompileEntry
+}
+
+// NewPrecompileManager returns an empty PrecompileManager that carries config.
+// Precompiles are added via Register; each entry's activation block then
+// decides, per block, whether it is resolvable. Resolve does not read config,
+// so activation does not depend on the chain's fork settings.
+func NewPrecompileManager(config *params.ChainConfig) *PrecompileManager {
+	return &PrecompileManager{
+		config:  config,
+		entries: make(map[common.Address]precompileEntry),
+	}
+}
+
+// Register adds (or replaces) the precompile at addr. activateBlock is the
+// block number at which it becomes resolvable; pass nil to activate it from
+// genesis.
+func (m *PrecompileManager) Register(addr common.Address, contract PrecompiledContract, activateBlock *uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[addr] = precompileEntry{contract: contract, activate: activateBlock}
+}
+
+// Resolve returns the precompile registered at addr, if any, and whether it
+// is active at blockNumber according to its activation table entry.
+func (m *PrecompileManager) Resolve(addr common.Address, blockNumber *big.Int) (PrecompiledContract, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entry, ok := m.entries[addr]
+	if !ok {
+		return nil, false
+	}
+	if entry.activate == nil {
+		return entry.contract, true
+	}
+	if blockNumber == nil {
+		return nil, false
+	}
+	return entry.contract, blockNumber.Uint64() >= *entry.activate
+}
+
+// preparer is implemented by precompiles that need to warm up per-call state
+// (e.g. reading an oracle's configuration account) before they are run.
+type preparer interface {
+	Prepare(ctx context.Context, statedb *state.StateDB)
+}
+
+// Prepare is called at an EVM construction site before the EVM is built (in
+// precacheTransaction, once per transaction), giving every registered
+// precompile that implements preparer a chance to do that warm-up.
+func (m *PrecompileManager) Prepare(ctx context.Context, statedb *state.StateDB) {
+	m.mu.RLock()
+	entries := make([]precompileEntry, 0, len(m.entries))
+	for _, entry := range m.entries {
+		entries = append(entries, entry)
+	}
+	m.mu.RUnlock()
+
+	for _, entry := range entries {
+		if p, ok := entry.contract.(preparer); ok {
+			p.Prepare(ctx, statedb)
+		}
+	}
+}
+
+// Snapshot returns a read-only copy of the registry's current entries. Hand
+// the snapshot to goroutines (such as prefetch workers) that need a stable
+// view for the duration of a block, so concurrent Register calls on the live
+// manager don't race with their reads.
+func (m *PrecompileManager) Snapshot() *PrecompileManager {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	entries := make(map[common.Address]precompileEntry, len(m.entries))
+	for addr, entry := range m.entries {
+		entries[addr] = entry
+	}
+	return &PrecompileManager{config: m.config, entries: entries}
+}
+
+// PrecompileInfo describes one registered precompile, as returned by the
+// admin_listPrecompiles RPC method.
+type PrecompileInfo struct {
+	Address       common.Address `json:"address"`
+	ActivateBlock *uint64        `json:"activateBlock"`
+}
+
+// List returns the currently registered set of precompiles, sorted by none
+// guarantee in particular; callers that need a stable order should sort.
+func (m *PrecompileManager) List() []PrecompileInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	infos := make([]PrecompileInfo, 0, len(m.entries))
+	for addr, entry := range m.entries {
+		infos = append(infos, PrecompileInfo{Address: addr, ActivateBlock: entry.activate})
+	}
+	return infos
+}
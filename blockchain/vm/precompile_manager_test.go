@@ -0,0 +1,106 @@
+// Modifications Copyright 2020 The klaytn Authors
+// This file is part of the klaytn library.
+//
+// The klaytn library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The klaytn library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the klaytn library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/klaytn/klaytn/blockchain/state"
+	"github.com/klaytn/klaytn/common"
+)
+
+type fakePrecompile struct {
+	prepared bool
+}
+
+func (f *fakePrecompile) RequiredGas(input []byte) uint64    { return 0 }
+func (f *fakePrecompile) Run(input []byte) ([]byte, error)   { return nil, nil }
+func (f *fakePrecompile) Prepare(ctx context.Context, statedb *state.StateDB) {
+	f.prepared = true
+}
+
+func TestPrecompileManagerResolve(t *testing.T) {
+	m := NewPrecompileManager(nil)
+	addr := common.HexToAddress("0x00000000000000000000000000000000000100")
+	contract := &fakePrecompile{}
+	activate := uint64(10)
+	m.Register(addr, contract, &activate)
+
+	if _, ok := m.Resolve(addr, big.NewInt(5)); ok {
+		t.Fatalf("expected precompile to be inactive before its activation block")
+	}
+	got, ok := m.Resolve(addr, big.NewInt(10))
+	if !ok || got != contract {
+		t.Fatalf("expected precompile to resolve at its activation block, got %v, %v", got, ok)
+	}
+	if _, ok := m.Resolve(common.HexToAddress("0x00000000000000000000000000000000000200"), big.NewInt(10)); ok {
+		t.Fatalf("expected unregistered address to not resolve")
+	}
+}
+
+func TestPrecompileManagerResolveGenesisActivation(t *testing.T) {
+	m := NewPrecompileManager(nil)
+	addr := common.HexToAddress("0x00000000000000000000000000000000000100")
+	contract := &fakePrecompile{}
+	m.Register(addr, contract, nil)
+
+	if _, ok := m.Resolve(addr, nil); !ok {
+		t.Fatalf("expected a nil-activation precompile to resolve regardless of block number")
+	}
+}
+
+func TestPrecompileManagerPrepare(t *testing.T) {
+	m := NewPrecompileManager(nil)
+	addr := common.HexToAddress("0x00000000000000000000000000000000000100")
+	contract := &fakePrecompile{}
+	m.Register(addr, contract, nil)
+
+	m.Prepare(context.Background(), nil)
+	if !contract.prepared {
+		t.Fatalf("expected Prepare to call through to the registered precompile's Prepare hook")
+	}
+}
+
+func TestPrecompileManagerSnapshotIsStable(t *testing.T) {
+	m := NewPrecompileManager(nil)
+	addr := common.HexToAddress("0x00000000000000000000000000000000000100")
+	m.Register(addr, &fakePrecompile{}, nil)
+
+	snap := m.Snapshot()
+	m.Register(common.HexToAddress("0x00000000000000000000000000000000000200"), &fakePrecompile{}, nil)
+
+	if len(snap.List()) != 1 {
+		t.Fatalf("expected snapshot to be unaffected by registrations made after it was taken, got %d entries", len(snap.List()))
+	}
+	if len(m.List()) != 2 {
+		t.Fatalf("expected the live manager to reflect the later registration, got %d entries", len(m.List()))
+	}
+}
+
+func TestPrecompileManagerList(t *testing.T) {
+	m := NewPrecompileManager(nil)
+	addr := common.HexToAddress("0x00000000000000000000000000000000000100")
+	activate := uint64(42)
+	m.Register(addr, &fakePrecompile{}, &activate)
+
+	infos := m.List()
+	if len(infos) != 1 || infos[0].Address != addr || infos[0].ActivateBlock == nil || *infos[0].ActivateBlock != activate {
+		t.Fatalf("unexpected List() result: %+v", infos)
+	}
+}
@@ -0,0 +1,25 @@
+package gxapi
+
+import (
+	klaytnvm "github.com/klaytn/klaytn/blockchain/vm"
+)
+
+// PrivateAdminAPI is the collection of administrative API methods exposed
+// only to trusted local users over the "admin" namespace.
+type PrivateAdminAPI struct {
+	b Backend
+}
+
+// NewPrivateAdminAPI creates a new API definition for the admin methods of
+// the node itself.
+func NewPrivateAdminAPI(b Backend) *PrivateAdminAPI {
+	return &PrivateAdminAPI{b: b}
+}
+
+// ListPrecompiles returns the precompiled contracts currently registered on
+// the node's PrecompileManager, exposed as the admin_listPrecompiles RPC
+// method. It lets an operator verify that a custom stateful precompile (e.g.
+// for an app-chain bridge or oracle) registered at startup actually took.
+func (api *PrivateAdminAPI) ListPrecompiles() []klaytnvm.PrecompileInfo {
+	return api.b.PrecompileManager().List()
+}
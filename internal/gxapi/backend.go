@@ -14,6 +14,8 @@ import (
 	"groundx.xyz/go-gxplatform/params"
 	"groundx.xyz/go-gxplatform/gxdb"
 	"groundx.xyz/go-gxplatform/gxp/downloader"
+
+	klaytnvm "github.com/klaytn/klaytn/blockchain/vm"
 )
 
 // Backend interface provides the common API services (that are provided by
@@ -51,6 +53,15 @@ type Backend interface {
 
 	ChainConfig() *params.ChainConfig
 	CurrentBlock() *types.Block
+
+	// PrecompileManager returns the registry of stateful precompiled contracts
+	// consulted by blockchain.BlockChain's own EVM construction sites (see
+	// blockchain/state_prefetcher.go), so operators can register app-chain
+	// specific precompiles at node startup. Note this is the klaytnvm
+	// registry, not the legacy groundx.xyz/go-gxplatform/core/vm (plain vm,
+	// above) that GetEVM and eth_call use; those are separate packages and
+	// this method does not make eth_call resolve against the registry.
+	PrecompileManager() *klaytnvm.PrecompileManager
 }
 
 func GetAPIs(apiBackend Backend) []rpc.API {
@@ -86,6 +97,11 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 			Version:   "1.0",
 			Service:   NewPrivateAccountAPI(apiBackend, nonceLock),
 			Public:    false,
+		}, {
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   NewPrivateAdminAPI(apiBackend),
+			Public:    false,
 		},
 	}
 }